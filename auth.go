@@ -0,0 +1,192 @@
+// Copyright 2013 Matthew Baird
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tableau4go
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SigninWithPAT signs in using a Tableau Personal Access Token, the
+// replacement for username/password auth on servers where it has been
+// disabled.
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Sign_In
+func (api *API) SigninWithPAT(tokenName, tokenSecret, contentURL string) error {
+	return api.SigninWithPATContext(context.Background(), tokenName, tokenSecret, contentURL)
+}
+
+// SigninWithPATContext is SigninWithPAT with a caller-supplied context.
+func (api *API) SigninWithPATContext(ctx context.Context, tokenName, tokenSecret, contentURL string) error {
+	credentials := Credentials{
+		PersonalAccessTokenName:   tokenName,
+		PersonalAccessTokenSecret: tokenSecret,
+	}
+	return api.signinWithCredentials(ctx, credentials, contentURL)
+}
+
+// SigninWithJWT signs in using a signed JWT issued for a Tableau Connected
+// App, letting server-to-server callers authenticate without storing a
+// user's password. Use NewConnectedAppJWT to build jwt.
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Sign_In
+func (api *API) SigninWithJWT(jwt, contentURL string) error {
+	return api.SigninWithJWTContext(context.Background(), jwt, contentURL)
+}
+
+// SigninWithJWTContext is SigninWithJWT with a caller-supplied context.
+func (api *API) SigninWithJWTContext(ctx context.Context, jwt, contentURL string) error {
+	return api.signinWithCredentials(ctx, Credentials{JWT: jwt}, contentURL)
+}
+
+// signinWithCredentials issues the shared auth/signin call for any
+// credentials variant (password, PAT, or JWT), mirroring SigninContext.
+func (api *API) signinWithCredentials(ctx context.Context, credentials Credentials, contentURL string) error {
+	url := fmt.Sprintf("%s/api/%s/auth/signin", api.Server, api.Version)
+	siteName := contentURL
+	if api.OmitDefaultSiteName {
+		if contentURL == api.DefaultSiteName {
+			siteName = ""
+		}
+	}
+	credentials.Site = &Site{ContentUrl: siteName}
+	request := SigninRequest{Request: credentials}
+	payload, contentType, err := api.marshalRequest(request)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{content_type_header: contentType}
+	retval := AuthResponse{}
+	err = api.makeRequest(ctx, url, POST, payload, &retval, headers, connectTimeOut, readWriteTimeout, api.Format)
+	if err == nil {
+		api.AuthToken = retval.Credentials.Token
+	}
+	return err
+}
+
+// connectedAppTokenTTL is how long a NewConnectedAppJWT token is valid for.
+// Tableau rejects Connected App JWTs with a longer lifetime than this.
+const connectedAppTokenTTL = 5 * time.Minute
+
+// connectedAppClaims is the claim set required by the Connected Apps direct
+// trust spec.
+// https://help.tableau.com/current/online/en-us/connected_apps_direct.htm
+type connectedAppClaims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Audience  string   `json:"aud"`
+	JWTID     string   `json:"jti"`
+	IssuedAt  int64    `json:"iat"`
+	ExpiresAt int64    `json:"exp"`
+	Scopes    []string `json:"scp"`
+}
+
+// NewConnectedAppJWT builds and signs a JWT suitable for SigninWithJWT, with
+// the iss, sub, aud, jti, exp and scp claims Connected Apps requires.
+// secretValue is used as an HS256 HMAC key unless it is a PEM-encoded RSA
+// private key, in which case the JWT is signed RS256.
+func NewConnectedAppJWT(clientID, secretID, secretValue, username string, scopes []string) (string, error) {
+	jti, err := newJWTID()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := connectedAppClaims{
+		Issuer:    clientID,
+		Subject:   username,
+		Audience:  "tableau",
+		JWTID:     jti,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(connectedAppTokenTTL).Unix(),
+		Scopes:    scopes,
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	alg := "HS256"
+	if isPEMEncodedKey(secretValue) {
+		alg = "RS256"
+	}
+	header, err := json.Marshal(map[string]string{"alg": alg, "typ": "JWT", "kid": secretID})
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64URLEncode(header) + "." + base64URLEncode(claimsJSON)
+	var signature []byte
+	if alg == "RS256" {
+		signature, err = signRS256(signingInput, secretValue)
+	} else {
+		signature = signHS256(signingInput, secretValue)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + base64URLEncode(signature), nil
+}
+
+func isPEMEncodedKey(secretValue string) bool {
+	return strings.Contains(secretValue, "-----BEGIN")
+}
+
+func signHS256(signingInput, secretValue string) []byte {
+	mac := hmac.New(sha256.New, []byte(secretValue))
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+func signRS256(signingInput, pemKey string) ([]byte, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, fmt.Errorf("could not decode PEM block from connected app secret")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, pkcs8Err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if pkcs8Err != nil {
+			return nil, fmt.Errorf("parsing RSA private key: %w", err)
+		}
+		rsaKey, ok := parsed.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("connected app secret is not an RSA private key")
+		}
+		key = rsaKey
+	}
+	hashed := sha256.Sum256([]byte(signingInput))
+	return key.Sign(rand.Reader, hashed[:], crypto.SHA256)
+}
+
+func newJWTID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func base64URLEncode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,106 @@
+// Copyright 2013 Matthew Baird
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tableau4go
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"testing"
+)
+
+func TestNewConnectedAppJWTSignsHS256(t *testing.T) {
+	token, err := NewConnectedAppJWT("client-id", "secret-id", "shared-secret", "jdoe", []string{"tableau:views:embed"})
+	if err != nil {
+		t.Fatalf("NewConnectedAppJWT: %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected a 3-part JWT, got %d parts", len(parts))
+	}
+
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		t.Fatalf("decoding header: %v", err)
+	}
+	var decodedHeader map[string]string
+	if err := json.Unmarshal(header, &decodedHeader); err != nil {
+		t.Fatalf("unmarshaling header: %v", err)
+	}
+	if decodedHeader["alg"] != "HS256" {
+		t.Fatalf("alg = %q, want HS256", decodedHeader["alg"])
+	}
+	if decodedHeader["kid"] != "secret-id" {
+		t.Fatalf("kid = %q, want secret-id", decodedHeader["kid"])
+	}
+
+	claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("decoding claims: %v", err)
+	}
+	var decodedClaims connectedAppClaims
+	if err := json.Unmarshal(claims, &decodedClaims); err != nil {
+		t.Fatalf("unmarshaling claims: %v", err)
+	}
+	if decodedClaims.Issuer != "client-id" || decodedClaims.Subject != "jdoe" {
+		t.Fatalf("unexpected claims: %+v", decodedClaims)
+	}
+	if decodedClaims.ExpiresAt-decodedClaims.IssuedAt != int64(connectedAppTokenTTL.Seconds()) {
+		t.Fatalf("exp-iat = %d, want %d", decodedClaims.ExpiresAt-decodedClaims.IssuedAt, int64(connectedAppTokenTTL.Seconds()))
+	}
+}
+
+func TestNewConnectedAppJWTSignsRS256WithPEMKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating RSA key: %v", err)
+	}
+	pemKey := string(pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: mustMarshalPKCS8(t, key),
+	}))
+
+	token, err := NewConnectedAppJWT("client-id", "secret-id", pemKey, "jdoe", nil)
+	if err != nil {
+		t.Fatalf("NewConnectedAppJWT: %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected a 3-part JWT, got %d parts", len(parts))
+	}
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		t.Fatalf("decoding header: %v", err)
+	}
+	var decodedHeader map[string]string
+	if err := json.Unmarshal(header, &decodedHeader); err != nil {
+		t.Fatalf("unmarshaling header: %v", err)
+	}
+	if decodedHeader["alg"] != "RS256" {
+		t.Fatalf("alg = %q, want RS256", decodedHeader["alg"])
+	}
+}
+
+func mustMarshalPKCS8(t *testing.T, key *rsa.PrivateKey) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshaling PKCS8 key: %v", err)
+	}
+	return der
+}
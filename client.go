@@ -13,6 +13,7 @@ package tableau4go
 
 import (
 	"bytes"
+	"context"
 	"encoding/csv"
 	"encoding/xml"
 	"errors"
@@ -31,6 +32,7 @@ const auth_header = "X-Tableau-Auth"
 const application_xml_content_type = "application/xml"
 const POST = "POST"
 const GET = "GET"
+const PUT = "PUT"
 const DELETE = "DELETE"
 
 var ErrDoesNotExist = errors.New("Does Not Exist")
@@ -38,9 +40,16 @@ var ErrDoesNotExist = errors.New("Does Not Exist")
 // Debug api interactions. Set to try to enable debugging.
 var Debug = false
 
-// Signin signs in using the given username, password and contentURL
-//http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Sign_In%3FTocPath%3DAPI%2520Reference%7C_____51
+// Signin signs in using the given username, password and contentURL. It is
+// a thin wrapper around SigninContext using context.Background().
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Sign_In%3FTocPath%3DAPI%2520Reference%7C_____51
 func (api *API) Signin(username, password string, contentURL string, userIDToImpersonate string) error {
+	return api.SigninContext(context.Background(), username, password, contentURL, userIDToImpersonate)
+}
+
+// SigninContext is Signin with a caller-supplied context for cancellation
+// and deadlines.
+func (api *API) SigninContext(ctx context.Context, username, password string, contentURL string, userIDToImpersonate string) error {
 	url := fmt.Sprintf("%s/api/%s/auth/signin", api.Server, api.Version)
 	credentials := Credentials{Name: username, Password: password}
 	if len(userIDToImpersonate) > 0 {
@@ -56,15 +65,14 @@ func (api *API) Signin(username, password string, contentURL string, userIDToImp
 	}
 	credentials.Site = &Site{ContentUrl: siteName}
 	request := SigninRequest{Request: credentials}
-	signInXML, err := request.XML()
+	payload, contentType, err := api.marshalRequest(request)
 	if err != nil {
 		return err
 	}
-	payload := string(signInXML)
 	headers := make(map[string]string)
-	headers[content_type_header] = application_xml_content_type
+	headers[content_type_header] = contentType
 	retval := AuthResponse{}
-	err = api.makeRequest(url, POST, []byte(payload), &retval, headers, connectTimeOut, readWriteTimeout, "")
+	err = api.makeRequest(ctx, url, POST, payload, &retval, headers, connectTimeOut, readWriteTimeout, api.Format)
 	if err == nil {
 		api.AuthToken = retval.Credentials.Token
 	}
@@ -72,106 +80,158 @@ func (api *API) Signin(username, password string, contentURL string, userIDToImp
 }
 
 // Signout signs the current user out of the tableau session.
-//http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Sign_Out%3FTocPath%3DAPI%2520Reference%7C_____52
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Sign_Out%3FTocPath%3DAPI%2520Reference%7C_____52
 func (api *API) Signout() error {
+	return api.SignoutContext(context.Background())
+}
+
+// SignoutContext is Signout with a caller-supplied context.
+func (api *API) SignoutContext(ctx context.Context) error {
 	url := fmt.Sprintf("%s/api/%s/auth/signout", api.Server, api.Version)
 	headers := make(map[string]string)
 	headers[content_type_header] = application_xml_content_type
-	err := api.makeRequest(url, POST, nil, nil, headers, connectTimeOut, readWriteTimeout, "")
+	err := api.makeRequest(ctx, url, POST, nil, nil, headers, connectTimeOut, readWriteTimeout, "")
 	return err
 }
 
 // ServerInfo returns server information for current Tableau server.
-//http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Server_Info%3FTocPath%3DAPI%2520Reference%7C__
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Server_Info%3FTocPath%3DAPI%2520Reference%7C__
 func (api *API) ServerInfo() (ServerInfo, error) {
+	return api.ServerInfoContext(context.Background())
+}
+
+// ServerInfoContext is ServerInfo with a caller-supplied context.
+func (api *API) ServerInfoContext(ctx context.Context) (ServerInfo, error) {
 	// this call only works on apiVersion 2.4 and up
 	url := fmt.Sprintf("%s/api/%s/serverinfo", api.Server, "2.4")
 	headers := make(map[string]string)
 	retval := ServerInfoResponse{}
-	err := api.makeRequest(url, GET, nil, &retval, headers, connectTimeOut, readWriteTimeout, "")
+	err := api.makeRequest(ctx, url, GET, nil, &retval, headers, connectTimeOut, readWriteTimeout, "")
 	return retval.ServerInfo, err
 }
 
 // QuerySites returns a list of sites.
-//http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Query_Sites%3FTocPath%3DAPI%2520Reference%7C_____40
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Query_Sites%3FTocPath%3DAPI%2520Reference%7C_____40
 func (api *API) QuerySites() ([]Site, error) {
+	return api.QuerySitesContext(context.Background())
+}
+
+// QuerySitesContext is QuerySites with a caller-supplied context.
+func (api *API) QuerySitesContext(ctx context.Context) ([]Site, error) {
 	url := fmt.Sprintf("%s/api/%s/sites/", api.Server, api.Version)
 	headers := make(map[string]string)
 	retval := QuerySitesResponse{}
-	err := api.makeRequest(url, GET, nil, &retval, headers, connectTimeOut, readWriteTimeout, "")
+	err := api.makeRequest(ctx, url, GET, nil, &retval, headers, connectTimeOut, readWriteTimeout, "")
 	return retval.Sites.Sites, err
 }
 
 // QuerySite returns a site by it LUID.
-//http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Query_Sites%3FTocPath%3DAPI%2520Reference%7C_____40
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Query_Sites%3FTocPath%3DAPI%2520Reference%7C_____40
 func (api *API) QuerySite(siteID string, includeStorage bool) (Site, error) {
+	return api.QuerySiteContext(context.Background(), siteID, includeStorage)
+}
+
+// QuerySiteContext is QuerySite with a caller-supplied context.
+func (api *API) QuerySiteContext(ctx context.Context, siteID string, includeStorage bool) (Site, error) {
 	url := fmt.Sprintf("%s/api/%s/sites/%s", api.Server, api.Version, siteID)
 	if includeStorage {
 		url += fmt.Sprintf("?includeStorage=%v", includeStorage)
 	}
-	return api.querySite(url)
+	return api.querySite(ctx, url)
 }
 
 // QuerySiteByName returns a site by its name.
-//http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Query_Sites%3FTocPath%3DAPI%2520Reference%7C_____40
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Query_Sites%3FTocPath%3DAPI%2520Reference%7C_____40
 func (api *API) QuerySiteByName(name string, includeStorage bool) (Site, error) {
-	return api.querySiteByKey("name", name, includeStorage)
+	return api.QuerySiteByNameContext(context.Background(), name, includeStorage)
+}
+
+// QuerySiteByNameContext is QuerySiteByName with a caller-supplied context.
+func (api *API) QuerySiteByNameContext(ctx context.Context, name string, includeStorage bool) (Site, error) {
+	return api.querySiteByKey(ctx, "name", name, includeStorage)
 }
 
 // QuerySiteByContentURL returns a site by its contentURL.
-//http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Query_Sites%3FTocPath%3DAPI%2520Reference%7C_____40
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Query_Sites%3FTocPath%3DAPI%2520Reference%7C_____40
 func (api *API) QuerySiteByContentURL(contentURL string, includeStorage bool) (Site, error) {
-	return api.querySiteByKey("contentURL", contentURL, includeStorage)
+	return api.QuerySiteByContentURLContext(context.Background(), contentURL, includeStorage)
+}
+
+// QuerySiteByContentURLContext is QuerySiteByContentURL with a
+// caller-supplied context.
+func (api *API) QuerySiteByContentURLContext(ctx context.Context, contentURL string, includeStorage bool) (Site, error) {
+	return api.querySiteByKey(ctx, "contentURL", contentURL, includeStorage)
 }
 
-//http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Query_Sites%3FTocPath%3DAPI%2520Reference%7C_____40
-func (api *API) querySiteByKey(key, value string, includeStorage bool) (Site, error) {
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Query_Sites%3FTocPath%3DAPI%2520Reference%7C_____40
+func (api *API) querySiteByKey(ctx context.Context, key, value string, includeStorage bool) (Site, error) {
 	url := fmt.Sprintf("%s/api/%s/sites/%s?key=%s", api.Server, api.Version, value, key)
 	if includeStorage {
 		url += fmt.Sprintf("&includeStorage=%v", includeStorage)
 	}
-	return api.querySite(url)
+	return api.querySite(ctx, url)
 }
 
-//http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Query_Sites%3FTocPath%3DAPI%2520Reference%7C_____40
-func (api *API) querySite(url string) (Site, error) {
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Query_Sites%3FTocPath%3DAPI%2520Reference%7C_____40
+func (api *API) querySite(ctx context.Context, url string) (Site, error) {
 	headers := make(map[string]string)
 	retval := QuerySiteResponse{}
-	err := api.makeRequest(url, GET, nil, &retval, headers, connectTimeOut, readWriteTimeout, "")
+	err := api.makeRequest(ctx, url, GET, nil, &retval, headers, connectTimeOut, readWriteTimeout, "")
 	return retval.Site, err
 }
 
 // QueryUserOnSite returns tne users currently on the given site.
-//http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Query_User_On_Site%3FTocPath%3DAPI%2520Reference%7C_____47
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Query_User_On_Site%3FTocPath%3DAPI%2520Reference%7C_____47
 func (api *API) QueryUserOnSite(siteID, userID string) (User, error) {
+	return api.QueryUserOnSiteContext(context.Background(), siteID, userID)
+}
+
+// QueryUserOnSiteContext is QueryUserOnSite with a caller-supplied context.
+func (api *API) QueryUserOnSiteContext(ctx context.Context, siteID, userID string) (User, error) {
 	url := fmt.Sprintf("%s/api/%s/sites/%s/users/%s", api.Server, api.Version, siteID, userID)
 	headers := make(map[string]string)
 	retval := QueryUserOnSiteResponse{}
-	err := api.makeRequest(url, GET, nil, &retval, headers, connectTimeOut, readWriteTimeout, "")
+	err := api.makeRequest(ctx, url, GET, nil, &retval, headers, connectTimeOut, readWriteTimeout, "")
 	return retval.User, err
 }
 
 // QueryProjects returns the projects for the given site id.
-//http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Query_Projects%3FTocPath%3DAPI%2520Reference%7C_____38
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Query_Projects%3FTocPath%3DAPI%2520Reference%7C_____38
 func (api *API) QueryProjects(siteID string) ([]Project, error) {
+	return api.QueryProjectsContext(context.Background(), siteID)
+}
+
+// QueryProjectsContext is QueryProjects with a caller-supplied context.
+func (api *API) QueryProjectsContext(ctx context.Context, siteID string) ([]Project, error) {
 	url := fmt.Sprintf("%s/api/%s/sites/%s/projects", api.Server, api.Version, siteID)
 	headers := make(map[string]string)
 	retval := QueryProjectsResponse{}
-	err := api.makeRequest(url, GET, nil, &retval, headers, connectTimeOut, readWriteTimeout, "")
+	err := api.makeRequest(ctx, url, GET, nil, &retval, headers, connectTimeOut, readWriteTimeout, "")
 	return retval.Projects.Projects, err
 }
 
 // QueryViews returns views for the given site.
 func (api *API) QueryViews(siteID string) ([]View, error) {
+	return api.QueryViewsContext(context.Background(), siteID)
+}
+
+// QueryViewsContext is QueryViews with a caller-supplied context.
+func (api *API) QueryViewsContext(ctx context.Context, siteID string) ([]View, error) {
 	url := fmt.Sprintf("%s/api/%s/sites/%s/views", api.Server, api.Version, siteID)
 	headers := make(map[string]string)
 	retval := QueryViewsResponse{}
-	err := api.makeRequest(url, GET, nil, &retval, headers, connectTimeOut, readWriteTimeout, "")
+	err := api.makeRequest(ctx, url, GET, nil, &retval, headers, connectTimeOut, readWriteTimeout, "")
 	return retval.Views.Views, err
 }
 
 // QueryWorkbookViews returns views for the given workbook
 func (api *API) QueryWorkbookViews(siteID, workbookID string, values url.Values) ([]View, error) {
+	return api.QueryWorkbookViewsContext(context.Background(), siteID, workbookID, values)
+}
+
+// QueryWorkbookViewsContext is QueryWorkbookViews with a caller-supplied
+// context.
+func (api *API) QueryWorkbookViewsContext(ctx context.Context, siteID, workbookID string, values url.Values) ([]View, error) {
 	params := values.Encode()
 	if params != "" {
 		params = "?" + params
@@ -179,22 +239,32 @@ func (api *API) QueryWorkbookViews(siteID, workbookID string, values url.Values)
 	url := fmt.Sprintf("%s/api/%s/sites/%s/workbooks/%s/views%s", api.Server, api.Version, siteID, workbookID, params)
 	headers := make(map[string]string)
 	retval := QueryViewsResponse{}
-	err := api.makeRequest(url, GET, nil, &retval, headers, connectTimeOut, readWriteTimeout, "")
+	err := api.makeRequest(ctx, url, GET, nil, &retval, headers, connectTimeOut, readWriteTimeout, "")
 	return retval.Views.Views, err
 }
 
 // QueryViewData returns csv data for the view
 func (api *API) QueryViewData(siteID, viewID string) (*csv.Reader, error) {
+	return api.QueryViewDataContext(context.Background(), siteID, viewID)
+}
+
+// QueryViewDataContext is QueryViewData with a caller-supplied context.
+func (api *API) QueryViewDataContext(ctx context.Context, siteID, viewID string) (*csv.Reader, error) {
 	url := fmt.Sprintf("%s/api/%s/sites/%s/views/%s/data", api.Server, api.Version, siteID, viewID)
 	headers := make(map[string]string)
 	// retVal := []byte{}
 	retVal := csv.Reader{}
-	err := api.makeRequest(url, GET, nil, &retVal, headers, 60*time.Second, 60*time.Second, "csv")
+	err := api.makeRequest(ctx, url, GET, nil, &retVal, headers, 60*time.Second, 60*time.Second, FormatCSV)
 	return &retVal, err
 }
 
 // QueryWorkbooks returns workbooks for the given workbook
 func (api *API) QueryWorkbooks(siteID string, values url.Values) ([]Workbook, error) {
+	return api.QueryWorkbooksContext(context.Background(), siteID, values)
+}
+
+// QueryWorkbooksContext is QueryWorkbooks with a caller-supplied context.
+func (api *API) QueryWorkbooksContext(ctx context.Context, siteID string, values url.Values) ([]Workbook, error) {
 	params := values.Encode()
 	if params != "" {
 		params = "?" + params
@@ -202,13 +272,19 @@ func (api *API) QueryWorkbooks(siteID string, values url.Values) ([]Workbook, er
 	url := fmt.Sprintf("%s/api/%s/sites/%s/workbooks%s", api.Server, api.Version, siteID, params)
 	headers := make(map[string]string)
 	retval := QueryWorkbooksResponse{}
-	err := api.makeRequest(url, GET, nil, &retval, headers, connectTimeOut, readWriteTimeout, "")
+	err := api.makeRequest(ctx, url, GET, nil, &retval, headers, connectTimeOut, readWriteTimeout, "")
 	return retval.Workbooks.Workbooks, err
 }
 
 // GetProjectByName returns project by the given name
 func (api *API) GetProjectByName(siteID, name string) (Project, error) {
-	projects, err := api.QueryProjects(siteID)
+	return api.GetProjectByNameContext(context.Background(), siteID, name)
+}
+
+// GetProjectByNameContext is GetProjectByName with a caller-supplied
+// context.
+func (api *API) GetProjectByNameContext(ctx context.Context, siteID, name string) (Project, error) {
+	projects, err := api.QueryProjectsContext(ctx, siteID)
 	if err != nil {
 		return Project{}, err
 	}
@@ -222,7 +298,12 @@ func (api *API) GetProjectByName(siteID, name string) (Project, error) {
 
 // GetProjectByID returns project by the given ID
 func (api *API) GetProjectByID(siteID, ID string) (Project, error) {
-	projects, err := api.QueryProjects(siteID)
+	return api.GetProjectByIDContext(context.Background(), siteID, ID)
+}
+
+// GetProjectByIDContext is GetProjectByID with a caller-supplied context.
+func (api *API) GetProjectByIDContext(ctx context.Context, siteID, ID string) (Project, error) {
+	projects, err := api.QueryProjectsContext(ctx, siteID)
 	if err != nil {
 		return Project{}, err
 	}
@@ -235,18 +316,29 @@ func (api *API) GetProjectByID(siteID, ID string) (Project, error) {
 }
 
 // QueryDatasources returns DataSources for the given site ID.
-//http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Query_Datasources%3FTocPath%3DAPI%2520Reference%7C_____33
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Query_Datasources%3FTocPath%3DAPI%2520Reference%7C_____33
 func (api *API) QueryDatasources(siteID string) ([]Datasource, error) {
+	return api.QueryDatasourcesContext(context.Background(), siteID)
+}
+
+// QueryDatasourcesContext is QueryDatasources with a caller-supplied
+// context.
+func (api *API) QueryDatasourcesContext(ctx context.Context, siteID string) ([]Datasource, error) {
 	url := fmt.Sprintf("%s/api/%s/sites/%s/datasources", api.Server, api.Version, siteID)
 	headers := make(map[string]string)
 	retval := QueryDatasourcesResponse{}
-	err := api.makeRequest(url, GET, nil, &retval, headers, connectTimeOut, readWriteTimeout, "")
+	err := api.makeRequest(ctx, url, GET, nil, &retval, headers, connectTimeOut, readWriteTimeout, "")
 	return retval.Datasources.Datasources, err
 }
 
 // GetSiteID returns Sites by site name.
 func (api *API) GetSiteID(siteName string) (string, error) {
-	site, err := api.QuerySiteByName(siteName, false)
+	return api.GetSiteIDContext(context.Background(), siteName)
+}
+
+// GetSiteIDContext is GetSiteID with a caller-supplied context.
+func (api *API) GetSiteIDContext(ctx context.Context, siteName string) (string, error) {
+	site, err := api.QuerySiteByNameContext(ctx, siteName, false)
 	if err != nil {
 		return "", err
 	}
@@ -254,30 +346,40 @@ func (api *API) GetSiteID(siteName string) (string, error) {
 }
 
 // CreateProject creates the given project
-//http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Create_Project%3FTocPath%3DAPI%2520Reference%7C_____14
-//POST /api/api-version/sites/site-id/projects
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Create_Project%3FTocPath%3DAPI%2520Reference%7C_____14
+// POST /api/api-version/sites/site-id/projects
 func (api *API) CreateProject(siteID string, project Project) (*Project, error) {
+	return api.CreateProjectContext(context.Background(), siteID, project)
+}
+
+// CreateProjectContext is CreateProject with a caller-supplied context.
+func (api *API) CreateProjectContext(ctx context.Context, siteID string, project Project) (*Project, error) {
 	url := fmt.Sprintf("%s/api/%s/sites/%s/projects", api.Server, api.Version, siteID)
 	createProjectRequest := CreateProjectRequest{Request: project}
-	xmlRep, err := createProjectRequest.XML()
+	payload, contentType, err := api.marshalRequest(createProjectRequest)
 	if err != nil {
 		return nil, err
 	}
 	headers := make(map[string]string)
-	headers[content_type_header] = application_xml_content_type
+	headers[content_type_header] = contentType
 	createProjectResponse := CreateProjectResponse{}
-	err = api.makeRequest(url, POST, xmlRep, &createProjectResponse, headers, connectTimeOut, readWriteTimeout, "")
+	err = api.makeRequest(ctx, url, POST, payload, &createProjectResponse, headers, connectTimeOut, readWriteTimeout, api.Format)
 	return &createProjectResponse.Project, err
 }
 
 // PublishTDS publishes the given datasource.
 // http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Publish_Datasource%3FTocPath%3DAPI%2520Reference%7C_____31
 func (api *API) PublishTDS(siteID string, tdsMetadata Datasource, fullTds string, overwrite bool) (retval *Datasource, err error) {
-	return api.publishDatasource(siteID, tdsMetadata, fullTds, "tds", overwrite)
+	return api.PublishTDSContext(context.Background(), siteID, tdsMetadata, fullTds, overwrite)
 }
 
-//http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Publish_Datasource%3FTocPath%3DAPI%2520Reference%7C_____31
-func (api *API) publishDatasource(siteID string, tdsMetadata Datasource, datasource string, datasourceType string, overwrite bool) (retval *Datasource, err error) {
+// PublishTDSContext is PublishTDS with a caller-supplied context.
+func (api *API) PublishTDSContext(ctx context.Context, siteID string, tdsMetadata Datasource, fullTds string, overwrite bool) (retval *Datasource, err error) {
+	return api.publishDatasource(ctx, siteID, tdsMetadata, fullTds, "tds", overwrite)
+}
+
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Publish_Datasource%3FTocPath%3DAPI%2520Reference%7C_____31
+func (api *API) publishDatasource(ctx context.Context, siteID string, tdsMetadata Datasource, datasource string, datasourceType string, overwrite bool) (retval *Datasource, err error) {
 	url := fmt.Sprintf("%s/api/%s/sites/%s/datasources?datasourceType=%s&overwrite=%v", api.Server, api.Version, siteID, datasourceType, overwrite)
 	payload := fmt.Sprintf("--%s\r\n", api.Boundary)
 	payload += "Content-Disposition: name=\"request_payload\"\r\n"
@@ -297,75 +399,117 @@ func (api *API) publishDatasource(siteID string, tdsMetadata Datasource, datasou
 	payload += fmt.Sprintf("\r\n--%s--\r\n", api.Boundary)
 	headers := make(map[string]string)
 	headers[content_type_header] = fmt.Sprintf("multipart/mixed; boundary=%s", api.Boundary)
-	err = api.makeRequest(url, POST, []byte(payload), retval, headers, connectTimeOut, readWriteTimeout, "")
+	err = api.makeRequest(ctx, url, POST, []byte(payload), retval, headers, connectTimeOut, readWriteTimeout, "")
 	return retval, err
 }
 
 // DeleteDatasource deletes a datasource with the given ID.
-//http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Delete_Datasource%3FTocPath%3DAPI%2520Reference%7C_____15
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Delete_Datasource%3FTocPath%3DAPI%2520Reference%7C_____15
 func (api *API) DeleteDatasource(siteID string, datasourceID string) error {
+	return api.DeleteDatasourceContext(context.Background(), siteID, datasourceID)
+}
+
+// DeleteDatasourceContext is DeleteDatasource with a caller-supplied
+// context.
+func (api *API) DeleteDatasourceContext(ctx context.Context, siteID string, datasourceID string) error {
 	url := fmt.Sprintf("%s/api/%s/sites/%s/datasources/%s", api.Server, api.Version, siteID, datasourceID)
-	return api.delete(url)
+	return api.delete(ctx, url)
 }
 
 // DeleteProject deletes the project with the given ID.
-//http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Delete_Project%3FTocPath%3DAPI%2520Reference%7C_____17
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Delete_Project%3FTocPath%3DAPI%2520Reference%7C_____17
 func (api *API) DeleteProject(siteID string, projectID string) error {
+	return api.DeleteProjectContext(context.Background(), siteID, projectID)
+}
+
+// DeleteProjectContext is DeleteProject with a caller-supplied context.
+func (api *API) DeleteProjectContext(ctx context.Context, siteID string, projectID string) error {
 	url := fmt.Sprintf("%s/api/%s/sites/%s/projects/%s", api.Server, api.Version, siteID, projectID)
-	return api.delete(url)
+	return api.delete(ctx, url)
 }
 
 // DeleteSite deletes the site with the given ID.
-//http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Delete_Project%3FTocPath%3DAPI%2520Reference%7C_____17
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Delete_Project%3FTocPath%3DAPI%2520Reference%7C_____17
 func (api *API) DeleteSite(siteID string) error {
+	return api.DeleteSiteContext(context.Background(), siteID)
+}
+
+// DeleteSiteContext is DeleteSite with a caller-supplied context.
+func (api *API) DeleteSiteContext(ctx context.Context, siteID string) error {
 	url := fmt.Sprintf("%s/api/%s/sites/%s", api.Server, api.Version, siteID)
-	return api.delete(url)
+	return api.delete(ctx, url)
 }
 
 // DeleteSiteByName deletes the site with the given name.
-//http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Delete_Site%3FTocPath%3DAPI%2520Reference%7C_____19
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Delete_Site%3FTocPath%3DAPI%2520Reference%7C_____19
 func (api *API) DeleteSiteByName(name string) error {
-	return api.deleteSiteByKey("name", name)
+	return api.DeleteSiteByNameContext(context.Background(), name)
+}
+
+// DeleteSiteByNameContext is DeleteSiteByName with a caller-supplied
+// context.
+func (api *API) DeleteSiteByNameContext(ctx context.Context, name string) error {
+	return api.deleteSiteByKey(ctx, "name", name)
 }
 
 // DeleteSiteByContentURL deletes the site with the given contentURL.
-//http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Delete_Site%3FTocPath%3DAPI%2520Reference%7C_____19
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Delete_Site%3FTocPath%3DAPI%2520Reference%7C_____19
 func (api *API) DeleteSiteByContentURL(contentURL string) error {
-	return api.deleteSiteByKey("contentUrl", contentURL)
+	return api.DeleteSiteByContentURLContext(context.Background(), contentURL)
 }
 
-//http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Delete_Site%3FTocPath%3DAPI%2520Reference%7C_____19
-func (api *API) deleteSiteByKey(key string, value string) error {
+// DeleteSiteByContentURLContext is DeleteSiteByContentURL with a
+// caller-supplied context.
+func (api *API) DeleteSiteByContentURLContext(ctx context.Context, contentURL string) error {
+	return api.deleteSiteByKey(ctx, "contentUrl", contentURL)
+}
+
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Delete_Site%3FTocPath%3DAPI%2520Reference%7C_____19
+func (api *API) deleteSiteByKey(ctx context.Context, key string, value string) error {
 	url := fmt.Sprintf("%s/api/%s/sites/%s?key=%s", api.Server, api.Version, value, key)
-	return api.delete(url)
+	return api.delete(ctx, url)
 }
 
-func (api *API) delete(url string) error {
+func (api *API) delete(ctx context.Context, url string) error {
 	headers := make(map[string]string)
-	return api.makeRequest(url, DELETE, nil, nil, headers, connectTimeOut, readWriteTimeout, "")
+	return api.makeRequest(ctx, url, DELETE, nil, nil, headers, connectTimeOut, readWriteTimeout, "")
 }
 
 // makeRequest calls the REST api with the given url, method and payload. The
-// format param when not blank will deserialize for that format, defaulting to XML.
-func (api *API) makeRequest(requestURL string, method string, payload []byte, result interface{}, headers map[string]string, cTimeout time.Duration, rwTimeout time.Duration, format string) error {
+// format param when not blank will deserialize for that format; when blank,
+// api.Format is used, and XML when that is unset too. ctx governs cancellation of both the request itself and any
+// retry/backoff waiting done by the underlying transport.
+func (api *API) makeRequest(ctx context.Context, requestURL string, method string, payload []byte, result interface{}, headers map[string]string, cTimeout time.Duration, rwTimeout time.Duration, format Format) error {
 	if Debug {
 		fmt.Printf("%s:%v\n", method, requestURL)
 		if payload != nil {
 			fmt.Printf("%v\n", string(payload))
 		}
 	}
-	client := NewTimeoutClient(cTimeout, rwTimeout, false)
+	effectiveFormat := format
+	if effectiveFormat == "" {
+		effectiveFormat = api.Format
+	}
+	if effectiveFormat == "" {
+		effectiveFormat = FormatXML
+	}
+	if cTimeout > 0 || rwTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cTimeout+rwTimeout)
+		defer cancel()
+	}
+	client := api.httpClient()
 	var req *http.Request
 	if len(payload) > 0 {
 		var httpErr error
-		req, httpErr = http.NewRequest(strings.TrimSpace(method), strings.TrimSpace(requestURL), bytes.NewBuffer(payload))
+		req, httpErr = http.NewRequestWithContext(ctx, strings.TrimSpace(method), strings.TrimSpace(requestURL), bytes.NewBuffer(payload))
 		if httpErr != nil {
 			return httpErr
 		}
 		req.Header.Add(content_length_header, strconv.Itoa(len(payload)))
 	} else {
 		var httpErr error
-		req, httpErr = http.NewRequest(strings.TrimSpace(method), strings.TrimSpace(requestURL), nil)
+		req, httpErr = http.NewRequestWithContext(ctx, strings.TrimSpace(method), strings.TrimSpace(requestURL), nil)
 		if httpErr != nil {
 			return httpErr
 		}
@@ -375,6 +519,9 @@ func (api *API) makeRequest(requestURL string, method string, payload []byte, re
 			req.Header.Add(header, headerValue)
 		}
 	}
+	if req.Header.Get("Accept") == "" && effectiveFormat != FormatCSV {
+		req.Header.Set("Accept", acceptHeaderFor(effectiveFormat))
+	}
 	if len(api.AuthToken) > 0 {
 		if Debug {
 			fmt.Printf("%s:%s\n", auth_header, api.AuthToken)
@@ -394,21 +541,27 @@ func (api *API) makeRequest(requestURL string, method string, payload []byte, re
 	if readBodyError != nil {
 		return readBodyError
 	}
-	if resp.StatusCode == 404 {
-		return ErrDoesNotExist
-	}
 	if resp.StatusCode >= 300 {
 		tErrorResponse := ErrorResponse{}
-		err := xml.Unmarshal(body, &tErrorResponse)
+		var err error
+		if effectiveFormat == FormatJSON {
+			err = unmarshalJSONResponse(body, &tErrorResponse)
+		} else {
+			err = xml.Unmarshal(body, &tErrorResponse)
+		}
 		if err != nil {
 			return err
 		}
-		return tErrorResponse.Error
+		return newAPIError(requestURL, resp.StatusCode, tErrorResponse.Error.Code, tErrorResponse.Error.Summary, tErrorResponse.Error.Detail)
 	}
 	if result != nil {
-		switch format {
-		case "csv":
+		switch effectiveFormat {
+		case FormatCSV:
 			*result.(*csv.Reader) = *csv.NewReader(bytes.NewReader(body))
+		case FormatJSON:
+			if err := unmarshalJSONResponse(body, result); err != nil {
+				return err
+			}
 		default:
 			if err := xml.Unmarshal(body, &result); err != nil {
 				return err
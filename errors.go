@@ -0,0 +1,90 @@
+// Copyright 2013 Matthew Baird
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tableau4go
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Typed sentinels an *APIError can be compared against with errors.Is, so
+// callers can branch on a failure class (re-signin on ErrUnauthorized,
+// conflict resolution on ErrConflict, ...) without parsing HTTP status
+// codes or Tableau's numeric error codes themselves.
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrConflict     = errors.New("conflict")
+	ErrRateLimited  = errors.New("rate limited")
+)
+
+// APIError is returned for any non-2xx response from the Tableau REST API
+// whose body decodes as a Tableau error document; when the body does not
+// decode, the decoding error is returned instead and carries no HTTP
+// status. It carries the HTTP status, Tableau's numeric error code (e.g. 401001
+// for invalid credentials, 409004 for a resource conflict), the
+// summary/detail text Tableau returned, and the URL that was requested.
+// Use errors.As to recover one from an error returned by this package, and
+// errors.Is against ErrUnauthorized, ErrForbidden, ErrConflict,
+// ErrRateLimited or ErrDoesNotExist to branch on the failure class.
+type APIError struct {
+	HTTPStatus int
+	Code       string
+	Summary    string
+	Detail     string
+	RequestURL string
+
+	sentinel error
+}
+
+func (e *APIError) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("tableau4go: %s (code %s, HTTP %d) for %s: %s", e.Summary, e.Code, e.HTTPStatus, e.RequestURL, e.Detail)
+	}
+	return fmt.Sprintf("tableau4go: %s (code %s, HTTP %d) for %s", e.Summary, e.Code, e.HTTPStatus, e.RequestURL)
+}
+
+// Unwrap exposes the typed sentinel classifying this error, so
+// errors.Is(err, ErrConflict) works on the *APIError returned by makeRequest.
+func (e *APIError) Unwrap() error {
+	return e.sentinel
+}
+
+// newAPIError builds the *APIError returned for a non-2xx Tableau
+// response, classifying it against the typed sentinels by HTTP status.
+func newAPIError(requestURL string, httpStatus int, code, summary, detail string) *APIError {
+	return &APIError{
+		HTTPStatus: httpStatus,
+		Code:       code,
+		Summary:    summary,
+		Detail:     detail,
+		RequestURL: requestURL,
+		sentinel:   sentinelForStatus(httpStatus),
+	}
+}
+
+func sentinelForStatus(httpStatus int) error {
+	switch httpStatus {
+	case 401:
+		return ErrUnauthorized
+	case 403:
+		return ErrForbidden
+	case 404:
+		return ErrDoesNotExist
+	case 409:
+		return ErrConflict
+	case 429:
+		return ErrRateLimited
+	default:
+		return nil
+	}
+}
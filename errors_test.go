@@ -0,0 +1,48 @@
+// Copyright 2013 Matthew Baird
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tableau4go
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSentinelForStatus(t *testing.T) {
+	cases := map[int]error{
+		401: ErrUnauthorized,
+		403: ErrForbidden,
+		404: ErrDoesNotExist,
+		409: ErrConflict,
+		429: ErrRateLimited,
+		500: nil,
+	}
+	for status, want := range cases {
+		if got := sentinelForStatus(status); got != want {
+			t.Errorf("sentinelForStatus(%d) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestNewAPIErrorUnwrapsToSentinel(t *testing.T) {
+	err := newAPIError("https://tableau.example.com/api/3.4/sites/x", 409, "409004", "Resource conflict", "already exists")
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("errors.Is(err, ErrConflict) = false, want true")
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("errors.As(err, &apiErr) = false, want true")
+	}
+	if apiErr.HTTPStatus != 409 || apiErr.Code != "409004" {
+		t.Fatalf("unexpected APIError fields: %+v", apiErr)
+	}
+}
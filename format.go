@@ -0,0 +1,72 @@
+// Copyright 2013 Matthew Baird
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tableau4go
+
+import "encoding/json"
+
+// Format selects the wire representation makeRequest uses for a call's
+// request body, Accept header, and response decoding.
+type Format string
+
+const (
+	// FormatXML is the REST API's original representation and remains the
+	// default when API.Format is unset.
+	FormatXML Format = "xml"
+	// FormatJSON requests and decodes application/json, available on
+	// modern Tableau Server/Cloud versions.
+	FormatJSON Format = "json"
+	// FormatCSV is used only by QueryViewData, whose response body is a
+	// CSV export rather than a tsResponse document.
+	FormatCSV Format = "csv"
+)
+
+const application_json_content_type = "application/json"
+
+// xmlMarshaler is implemented by the *Request wrapper types (SigninRequest,
+// CreateProjectRequest, ...), each of which already knows how to render
+// itself as the tsRequest XML document the REST API expects.
+type xmlMarshaler interface {
+	XML() ([]byte, error)
+}
+
+// marshalRequest renders v as the request body for api.Format, returning the
+// body alongside the Content-Type header it should be sent with. XML uses
+// v's existing XML() method; JSON falls back to encoding/json, relying on
+// the json struct tags carried by the same request/response types.
+func (api *API) marshalRequest(v xmlMarshaler) ([]byte, string, error) {
+	if api.Format == FormatJSON {
+		body, err := json.Marshal(v)
+		return body, application_json_content_type, err
+	}
+	body, err := v.XML()
+	return body, application_xml_content_type, err
+}
+
+// acceptHeaderFor returns the Accept header value for format, defaulting to
+// XML for the zero value and the CSV export format.
+func acceptHeaderFor(format Format) string {
+	if format == FormatJSON {
+		return application_json_content_type
+	}
+	return application_xml_content_type
+}
+
+// unmarshalJSONResponse decodes a Tableau JSON response body into result,
+// unwrapping the {"tsResponse": {...}} envelope every JSON response carries.
+// XML responses need no equivalent step since each response type's XMLName
+// field already matches the <tsResponse> root via its own "tsResponse" tag.
+func unmarshalJSONResponse(body []byte, result interface{}) error {
+	envelope := struct {
+		TsResponse interface{} `json:"tsResponse"`
+	}{TsResponse: result}
+	return json.Unmarshal(body, &envelope)
+}
@@ -0,0 +1,49 @@
+// Copyright 2013 Matthew Baird
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tableau4go
+
+import "testing"
+
+func TestUnmarshalJSONResponseUnwrapsTsResponseEnvelope(t *testing.T) {
+	body := []byte(`{
+		"tsResponse": {
+			"credentials": {
+				"token": "abc123",
+				"site": {"id": "site-id", "contentUrl": "default"},
+				"user": {"id": "user-id"}
+			}
+		}
+	}`)
+
+	var result AuthResponse
+	if err := unmarshalJSONResponse(body, &result); err != nil {
+		t.Fatalf("unmarshalJSONResponse: %v", err)
+	}
+	if result.Credentials.Token != "abc123" {
+		t.Fatalf("Credentials.Token = %q, want abc123", result.Credentials.Token)
+	}
+	if result.Credentials.Site == nil || result.Credentials.Site.ID != "site-id" {
+		t.Fatalf("Credentials.Site = %+v, want ID site-id", result.Credentials.Site)
+	}
+}
+
+func TestUnmarshalJSONResponseUnwrapsErrorResponse(t *testing.T) {
+	body := []byte(`{"tsResponse":{"error":{"code":"409004","summary":"Conflict","detail":"already exists"}}}`)
+
+	var result ErrorResponse
+	if err := unmarshalJSONResponse(body, &result); err != nil {
+		t.Fatalf("unmarshalJSONResponse: %v", err)
+	}
+	if result.Error.Code != "409004" || result.Error.Summary != "Conflict" {
+		t.Fatalf("unexpected Error: %+v", result.Error)
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright 2013 Matthew Baird
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tableau4go
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryPolicy controls how the transport built by httpClient retries
+// transient failures (5xx responses and 429 Too Many Requests).
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// DefaultRetryPolicy retries up to 3 times with exponential backoff
+// starting at 250ms and capped at 5s; jitter then picks each wait between
+// half and all of that delay.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  250 * time.Millisecond,
+	MaxDelay:   5 * time.Second,
+}
+
+// RateLimiter throttles outgoing requests. Wait blocks until a request may
+// proceed, or returns ctx.Err() if ctx is done first.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// NewTokenBucketLimiter returns a RateLimiter that allows ratePerSecond
+// requests per second on average, with bursts up to burst requests.
+func NewTokenBucketLimiter(ratePerSecond float64, burst int) RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucketLimiter{
+		tokens:     float64(burst),
+		ratePerSec: ratePerSecond,
+		burst:      float64(burst),
+		last:       time.Now(),
+	}
+}
+
+type tokenBucketLimiter struct {
+	mu         sync.Mutex
+	tokens     float64
+	ratePerSec float64
+	burst      float64
+	last       time.Time
+}
+
+func (t *tokenBucketLimiter) Wait(ctx context.Context) error {
+	for {
+		t.mu.Lock()
+		now := time.Now()
+		t.tokens += now.Sub(t.last).Seconds() * t.ratePerSec
+		if t.tokens > t.burst {
+			t.tokens = t.burst
+		}
+		t.last = now
+		if t.tokens >= 1 {
+			t.tokens--
+			t.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - t.tokens) / t.ratePerSec * float64(time.Second))
+		t.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// perHostLimiters lazily creates one RateLimiter per host so a single
+// API.RatePerSecond setting throttles each Tableau host independently.
+type perHostLimiters struct {
+	mu       sync.Mutex
+	limiters map[string]RateLimiter
+	new      func() RateLimiter
+}
+
+func (p *perHostLimiters) forHost(host string) RateLimiter {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	rl, ok := p.limiters[host]
+	if !ok {
+		rl = p.new()
+		p.limiters[host] = rl
+	}
+	return rl
+}
+
+// retryTransport wraps an http.RoundTripper with rate limiting and
+// exponential-backoff retries on 5xx/429 responses, honoring Retry-After
+// when the server sends it as a number of seconds (an HTTP-date value is
+// ignored and the backoff delay is used). Retries are only ever attempted for
+// idempotentMethods requests: a timeout on a POST/PUT (publish, chunk
+// append, commit, ...) may have already been processed by the server, and
+// blindly re-sending it risks double-creating a resource or corrupting an
+// in-progress chunked upload.
+type retryTransport struct {
+	next     http.RoundTripper
+	policy   RetryPolicy
+	limiters *perHostLimiters
+}
+
+// idempotentMethods are safe to retry automatically: none of them have a
+// side effect that repeating would change, so a lost response (timeout,
+// connection reset) can be retried without risking the server having
+// already applied a mutation the caller would otherwise unknowingly repeat.
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodDelete:  true,
+}
+
+func (rt *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if rt.limiters != nil {
+		if err := rt.limiters.forHost(req.URL.Host).Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+
+	if !idempotentMethods[req.Method] {
+		return rt.next.RoundTrip(req)
+	}
+
+	var bodyBytes []byte
+	if req.Body != nil {
+		var err error
+		bodyBytes, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var resp *http.Response
+	var err error
+	for attempt := 0; ; attempt++ {
+		if bodyBytes != nil {
+			req.Body = ioutil.NopCloser(bytes.NewReader(bodyBytes))
+		}
+		resp, err = rt.next.RoundTrip(req)
+		if err == nil && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return resp, nil
+		}
+		if attempt >= rt.policy.MaxRetries {
+			return resp, err
+		}
+		delay := rt.retryDelay(attempt, resp)
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (rt *retryTransport) retryDelay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if ra := resp.Header.Get("Retry-After"); ra != "" {
+			if secs, err := strconv.Atoi(ra); err == nil {
+				return time.Duration(secs) * time.Second
+			}
+		}
+	}
+	delay := rt.policy.BaseDelay * time.Duration(int64(1)<<uint(attempt))
+	if delay > rt.policy.MaxDelay {
+		delay = rt.policy.MaxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay)/2+1))
+}
+
+// httpClient returns the http.Client used to make requests, building it
+// once per API and reusing it on every subsequent call. This matters
+// beyond just avoiding rework: api.RatePerSecond is enforced by a token
+// bucket carried on the transport, so handing out a freshly-built bucket
+// (at full burst) on every call would make rate limiting a no-op. If
+// api.HTTPClient is set, callers get full control and it is returned as-is.
+func (api *API) httpClient() *http.Client {
+	if api.HTTPClient != nil {
+		return api.HTTPClient
+	}
+	api.clientOnce.Do(func() {
+		client := NewTimeoutClient(connectTimeOut, readWriteTimeout, false)
+		policy := api.RetryPolicy
+		if policy == (RetryPolicy{}) {
+			policy = DefaultRetryPolicy
+		}
+		next := client.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		rt := &retryTransport{next: next, policy: policy}
+		if api.RatePerSecond > 0 {
+			ratePerSecond, burst := api.RatePerSecond, api.RateBurst
+			rt.limiters = &perHostLimiters{
+				limiters: make(map[string]RateLimiter),
+				new:      func() RateLimiter { return NewTokenBucketLimiter(ratePerSecond, burst) },
+			}
+		}
+		client.Transport = rt
+		api.client = client
+	})
+	return api.client
+}
@@ -0,0 +1,76 @@
+// Copyright 2013 Matthew Baird
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tableau4go
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryDelayHonorsRetryAfter(t *testing.T) {
+	rt := &retryTransport{policy: DefaultRetryPolicy}
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"2"}}}
+	if got := rt.retryDelay(0, resp); got != 2*time.Second {
+		t.Fatalf("retryDelay with Retry-After: got %v, want %v", got, 2*time.Second)
+	}
+}
+
+func TestRetryDelayBacksOffAndCapsAtMaxDelay(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: 500 * time.Millisecond}
+	rt := &retryTransport{policy: policy}
+	for attempt := 0; attempt < 10; attempt++ {
+		delay := rt.retryDelay(attempt, nil)
+		if delay > policy.MaxDelay {
+			t.Fatalf("retryDelay(%d) = %v, exceeds MaxDelay %v", attempt, delay, policy.MaxDelay)
+		}
+		if delay < 0 {
+			t.Fatalf("retryDelay(%d) = %v, negative", attempt, delay)
+		}
+	}
+	if delay := rt.retryDelay(2, nil); delay < 2*policy.BaseDelay {
+		t.Fatalf("retryDelay(2) = %v, want at least %v after backing off", delay, 2*policy.BaseDelay)
+	}
+}
+
+func TestTokenBucketLimiterAllowsBurstThenThrottles(t *testing.T) {
+	limiter := NewTokenBucketLimiter(1000, 2)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatalf("Wait() within burst: %v", err)
+		}
+	}
+
+	start := time.Now()
+	if err := limiter.Wait(ctx); err != nil {
+		t.Fatalf("Wait() after burst: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed <= 0 {
+		t.Fatalf("Wait() after burst returned instantly, expected to block for a refill")
+	}
+}
+
+func TestTokenBucketLimiterRespectsContextCancellation(t *testing.T) {
+	limiter := NewTokenBucketLimiter(0.001, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(ctx); err != nil {
+		t.Fatalf("first Wait() should consume the initial burst token: %v", err)
+	}
+	if err := limiter.Wait(ctx); err == nil {
+		t.Fatal("Wait() should have returned ctx.Err() once the context deadline passed")
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright 2013 Matthew Baird
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tableau4go
+
+import (
+	"crypto/tls"
+	"encoding/xml"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// API is the client for a single Tableau Server/Cloud site. Server and
+// Version identify the host and REST API version to call; Boundary is the
+// multipart boundary used by the publish endpoints; AuthToken is populated
+// by a successful Signin call and sent as X-Tableau-Auth on every
+// subsequent request.
+type API struct {
+	Server              string
+	Version             string
+	Boundary            string
+	AuthToken           string
+	OmitDefaultSiteName bool
+	DefaultSiteName     string
+
+	// Format selects XML (the zero value) or JSON for request bodies, the
+	// Accept header, and response decoding. The publish calls send their
+	// request_payload part as XML whatever Format is, and PublishWorkbook
+	// also sends no Accept header and always decodes responses as XML.
+	Format Format
+
+	// HTTPClient, when set, is used as-is for every request, bypassing the
+	// retrying/rate-limited transport httpClient would otherwise build.
+	HTTPClient *http.Client
+
+	// RetryPolicy configures the default transport's retry behavior.
+	// DefaultRetryPolicy is used when this is the zero value.
+	RetryPolicy RetryPolicy
+
+	// RatePerSecond, when > 0, caps outgoing requests per host to this
+	// many per second on average, with RateBurst allowed as a burst.
+	RatePerSecond float64
+	RateBurst     int
+
+	// clientOnce/client cache the *http.Client httpClient builds around
+	// RetryPolicy/RatePerSecond, so the rate limiter's token bucket is
+	// shared across calls instead of being rebuilt (and refilled to a
+	// full burst) on every one.
+	clientOnce sync.Once
+	client     *http.Client
+}
+
+// connectTimeOut and readWriteTimeout are the dial and per-read/write
+// deadlines of the transport httpClient builds, and the values most calls
+// pass to makeRequest, which bounds each call by their sum. The CSV export
+// endpoint passes 60s for both, which lengthens only that overall bound;
+// the transport's dial and read/write deadlines stay at these values.
+var (
+	connectTimeOut   = 30 * time.Second
+	readWriteTimeout = 30 * time.Second
+)
+
+// NewTimeoutClient returns an *http.Client whose connections are subject to
+// connectTimeout for dialing and readWriteTimeout for every subsequent read
+// or write, since http.Client alone has no way to bound those separately.
+func NewTimeoutClient(connectTimeout, readWriteTimeout time.Duration, insecureSkipVerify bool) *http.Client {
+	transport := &http.Transport{
+		Dial: func(network, addr string) (net.Conn, error) {
+			conn, err := net.DialTimeout(network, addr, connectTimeout)
+			if err != nil {
+				return nil, err
+			}
+			return &deadlineConn{Conn: conn, timeout: readWriteTimeout}, nil
+		},
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: insecureSkipVerify},
+	}
+	return &http.Client{Transport: transport}
+}
+
+// deadlineConn resets its read/write deadline to timeout before every
+// operation, turning a single connect timeout into an ongoing idle timeout.
+type deadlineConn struct {
+	net.Conn
+	timeout time.Duration
+}
+
+func (c *deadlineConn) Read(b []byte) (int, error) {
+	if err := c.Conn.SetReadDeadline(time.Now().Add(c.timeout)); err != nil {
+		return 0, err
+	}
+	return c.Conn.Read(b)
+}
+
+func (c *deadlineConn) Write(b []byte) (int, error) {
+	if err := c.Conn.SetWriteDeadline(time.Now().Add(c.timeout)); err != nil {
+		return 0, err
+	}
+	return c.Conn.Write(b)
+}
+
+// User is the <user> element, e.g. the account to impersonate on Signin or
+// the result of QueryUserOnSite.
+type User struct {
+	ID string `xml:"id,attr" json:"id"`
+}
+
+// Site is the <site> element.
+type Site struct {
+	ID         string `xml:"id,attr" json:"id"`
+	Name       string `xml:"name,attr" json:"name"`
+	ContentUrl string `xml:"contentUrl,attr" json:"contentUrl"`
+}
+
+// Project is the <project> element.
+type Project struct {
+	ID   string `xml:"id,attr" json:"id"`
+	Name string `xml:"name,attr" json:"name"`
+}
+
+// Workbook is the <workbook> element.
+type Workbook struct {
+	ID   string `xml:"id,attr" json:"id"`
+	Name string `xml:"name,attr" json:"name"`
+}
+
+// Datasource is the <datasource> element.
+type Datasource struct {
+	ID   string `xml:"id,attr" json:"id"`
+	Name string `xml:"name,attr" json:"name"`
+}
+
+// View is the <view> element.
+type View struct {
+	ID   string `xml:"id,attr" json:"id"`
+	Name string `xml:"name,attr" json:"name"`
+}
+
+// ServerInfo is the <serverInfo> element returned by ServerInfo. Build is
+// carried as an attribute on the nested <productVersion> element
+// (`<productVersion build="...">X</productVersion>`), not on <serverInfo>
+// itself, so ProductVersion is its own struct rather than a plain string.
+type ServerInfo struct {
+	ProductVersion struct {
+		Build string `xml:"build,attr" json:"build"`
+		Value string `xml:",chardata" json:"value"`
+	} `xml:"productVersion" json:"productVersion"`
+	RestApiVersion string `xml:"restApiVersion" json:"restApiVersion"`
+}
+
+// TError is the <error> element Tableau returns for any non-2xx response.
+type TError struct {
+	Code    string `xml:"code,attr" json:"code"`
+	Summary string `xml:"summary" json:"summary"`
+	Detail  string `xml:"detail" json:"detail"`
+}
+
+func (e TError) Error() string {
+	return fmt.Sprintf("%s: %s (code %s)", e.Summary, e.Detail, e.Code)
+}
+
+// ErrorResponse is the <tsResponse> envelope around an <error>.
+type ErrorResponse struct {
+	XMLName xml.Name `xml:"tsResponse" json:"-"`
+	Error   TError   `xml:"error" json:"error"`
+}
+
+// Credentials is the <credentials> element posted to auth/signin. Exactly
+// one of Name/Password, PersonalAccessTokenName/PersonalAccessTokenSecret
+// or JWT is set, depending on which SigninXxx method built it.
+type Credentials struct {
+	Name     string `xml:"name,attr,omitempty" json:"name,omitempty"`
+	Password string `xml:"password,attr,omitempty" json:"password,omitempty"`
+	Token    string `xml:"token,attr,omitempty" json:"token,omitempty"`
+
+	PersonalAccessTokenName   string `xml:"personalAccessTokenName,attr,omitempty" json:"personalAccessTokenName,omitempty"`
+	PersonalAccessTokenSecret string `xml:"personalAccessTokenSecret,attr,omitempty" json:"personalAccessTokenSecret,omitempty"`
+
+	JWT string `xml:"jwt,attr,omitempty" json:"jwt,omitempty"`
+
+	Impersonate *User `xml:"user,omitempty" json:"user,omitempty"`
+	Site        *Site `xml:"site,omitempty" json:"site,omitempty"`
+}
+
+// SigninRequest is the <tsRequest> envelope posted to auth/signin.
+type SigninRequest struct {
+	XMLName xml.Name    `xml:"tsRequest" json:"-"`
+	Request Credentials `xml:"credentials" json:"credentials"`
+}
+
+func (r SigninRequest) XML() ([]byte, error) {
+	return xml.Marshal(r)
+}
+
+// AuthResponse is the <tsResponse> returned by auth/signin.
+type AuthResponse struct {
+	XMLName     xml.Name    `xml:"tsResponse" json:"-"`
+	Credentials Credentials `xml:"credentials" json:"credentials"`
+}
+
+// CreateProjectRequest is the <tsRequest> envelope posted to CreateProject.
+type CreateProjectRequest struct {
+	XMLName xml.Name `xml:"tsRequest" json:"-"`
+	Request Project  `xml:"project" json:"project"`
+}
+
+func (r CreateProjectRequest) XML() ([]byte, error) {
+	return xml.Marshal(r)
+}
+
+// CreateProjectResponse is the <tsResponse> returned by CreateProject.
+type CreateProjectResponse struct {
+	XMLName xml.Name `xml:"tsResponse" json:"-"`
+	Project Project  `xml:"project" json:"project"`
+}
+
+// DatasourceCreateRequest is the <tsRequest> envelope for the
+// request_payload part of a datasource publish.
+type DatasourceCreateRequest struct {
+	XMLName xml.Name   `xml:"tsRequest" json:"-"`
+	Request Datasource `xml:"datasource" json:"datasource"`
+}
+
+func (r DatasourceCreateRequest) XML() ([]byte, error) {
+	return xml.Marshal(r)
+}
+
+// WorkbookCreateRequest is the <tsRequest> envelope for the request_payload
+// part of a workbook publish, mirroring DatasourceCreateRequest.
+type WorkbookCreateRequest struct {
+	XMLName xml.Name `xml:"tsRequest" json:"-"`
+	Request Workbook `xml:"workbook" json:"workbook"`
+}
+
+func (r WorkbookCreateRequest) XML() ([]byte, error) {
+	return xml.Marshal(r)
+}
+
+// QuerySitesResponse is the <tsResponse> returned by QuerySites.
+type QuerySitesResponse struct {
+	XMLName xml.Name `xml:"tsResponse" json:"-"`
+	Sites   struct {
+		Sites []Site `xml:"site" json:"site"`
+	} `xml:"sites" json:"sites"`
+}
+
+// QuerySiteResponse is the <tsResponse> returned by QuerySite.
+type QuerySiteResponse struct {
+	XMLName xml.Name `xml:"tsResponse" json:"-"`
+	Site    Site     `xml:"site" json:"site"`
+}
+
+// QueryUserOnSiteResponse is the <tsResponse> returned by QueryUserOnSite.
+type QueryUserOnSiteResponse struct {
+	XMLName xml.Name `xml:"tsResponse" json:"-"`
+	User    User     `xml:"user" json:"user"`
+}
+
+// QueryProjectsResponse is the <tsResponse> returned by QueryProjects.
+type QueryProjectsResponse struct {
+	XMLName  xml.Name `xml:"tsResponse" json:"-"`
+	Projects struct {
+		Projects []Project `xml:"project" json:"project"`
+	} `xml:"projects" json:"projects"`
+}
+
+// QueryViewsResponse is the <tsResponse> returned by QueryViews and
+// QueryWorkbookViews.
+type QueryViewsResponse struct {
+	XMLName xml.Name `xml:"tsResponse" json:"-"`
+	Views   struct {
+		Views []View `xml:"view" json:"view"`
+	} `xml:"views" json:"views"`
+}
+
+// QueryWorkbooksResponse is the <tsResponse> returned by QueryWorkbooks.
+type QueryWorkbooksResponse struct {
+	XMLName   xml.Name `xml:"tsResponse" json:"-"`
+	Workbooks struct {
+		Workbooks []Workbook `xml:"workbook" json:"workbook"`
+	} `xml:"workbooks" json:"workbooks"`
+}
+
+// QueryDatasourcesResponse is the <tsResponse> returned by QueryDatasources.
+type QueryDatasourcesResponse struct {
+	XMLName     xml.Name `xml:"tsResponse" json:"-"`
+	Datasources struct {
+		Datasources []Datasource `xml:"datasource" json:"datasource"`
+	} `xml:"datasources" json:"datasources"`
+}
+
+// ServerInfoResponse is the <tsResponse> returned by ServerInfo.
+type ServerInfoResponse struct {
+	XMLName    xml.Name   `xml:"tsResponse" json:"-"`
+	ServerInfo ServerInfo `xml:"serverInfo" json:"serverInfo"`
+}
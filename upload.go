@@ -0,0 +1,297 @@
+// Copyright 2013 Matthew Baird
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tableau4go
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+)
+
+// defaultChunkSize is used when UploadOptions.ChunkSize is unset. Tableau
+// recommends chunks in the 5-50MB range; 32MB is a reasonable middle ground.
+// It also doubles as the default threshold above which PublishWorkbook
+// switches from a single multipart request to the fileUploads
+// initiate/append/commit sequence — see PublishWorkbook.
+const defaultChunkSize int64 = 32 << 20
+
+// ProgressFunc is called after each chunk of a streamed publish is sent.
+// totalBytes is 0 when the caller didn't supply a known size.
+type ProgressFunc func(bytesSent, totalBytes int64)
+
+// UploadOptions configures a streamed publish. The zero value uses
+// defaultChunkSize and reports no progress.
+type UploadOptions struct {
+	// ChunkSize is the size in bytes of each chunk appended to a file
+	// upload session. Defaults to defaultChunkSize when <= 0.
+	ChunkSize int64
+	// Progress, if set, is invoked after every chunk is written. It is not
+	// called when the payload is sent as a single multipart request.
+	Progress ProgressFunc
+}
+
+// FileUpload is the <fileUpload> element returned by the initiate and
+// append file upload endpoints.
+type FileUpload struct {
+	UploadSessionID string `xml:"uploadSessionId,attr"`
+}
+
+// FileUploadResponse wraps a FileUpload in the standard tsResponse envelope.
+type FileUploadResponse struct {
+	FileUpload FileUpload `xml:"fileUpload"`
+}
+
+// PublishWorkbook publishes a workbook by streaming r instead of buffering
+// the entire payload in memory, which makes it usable for real-world .twbx
+// files that can be hundreds of MB. size is the total number of bytes r
+// will yield; pass 0 if unknown, which forces the chunked path.
+//
+// Payloads at or under opts.ChunkSize (defaultChunkSize if opts is nil or
+// ChunkSize is unset) are sent as a single streamed multipart request.
+// Larger payloads are sent via the fileUploads initiate/append/commit
+// sequence described at
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Publish_Workbook
+func (api *API) PublishWorkbook(ctx context.Context, siteID string, meta Workbook, r io.Reader, size int64, overwrite bool, opts *UploadOptions) (retval *Workbook, err error) {
+	if opts == nil {
+		opts = &UploadOptions{}
+	}
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+
+	if size > 0 && size <= chunkSize {
+		return api.publishWorkbookSinglePart(ctx, siteID, meta, r, overwrite)
+	}
+	return api.publishWorkbookChunked(ctx, siteID, meta, r, size, overwrite, chunkSize, opts.Progress)
+}
+
+// publishWorkbookSinglePart streams r directly into the multipart request
+// body via an io.Pipe, so the whole file is never held in memory at once.
+func (api *API) publishWorkbookSinglePart(ctx context.Context, siteID string, meta Workbook, r io.Reader, overwrite bool) (retval *Workbook, err error) {
+	requestURL := fmt.Sprintf("%s/api/%s/sites/%s/workbooks?overwrite=%v", api.Server, api.Version, siteID, overwrite)
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	if err := mw.SetBoundary(api.Boundary); err != nil {
+		return nil, fmt.Errorf("invalid multipart boundary %q: %w", api.Boundary, err)
+	}
+
+	go func() {
+		var writeErr error
+		defer func() {
+			pw.CloseWithError(writeErr)
+		}()
+		requestPayloadWriter, writeErr := mw.CreatePart(partHeader("request_payload", "text/xml"))
+		if writeErr != nil {
+			return
+		}
+		workbookRequest := WorkbookCreateRequest{Request: meta}
+		xmlRepresentation, marshalErr := workbookRequest.XML()
+		if marshalErr != nil {
+			writeErr = marshalErr
+			return
+		}
+		if _, writeErr = requestPayloadWriter.Write(xmlRepresentation); writeErr != nil {
+			return
+		}
+		filePartWriter, writeErr := mw.CreatePart(fileDispositionHeader("tableau_workbook", meta.Name+".twbx"))
+		if writeErr != nil {
+			return
+		}
+		if _, writeErr = io.Copy(filePartWriter, r); writeErr != nil {
+			return
+		}
+		writeErr = mw.Close()
+	}()
+
+	headers := map[string]string{
+		content_type_header: fmt.Sprintf("multipart/mixed; boundary=%s", api.Boundary),
+	}
+	retval = &Workbook{}
+	err = api.makeStreamingRequest(ctx, requestURL, POST, pr, headers, retval)
+	return retval, err
+}
+
+// publishWorkbookChunked uploads r in chunkSize pieces via the fileUploads
+// initiate/append sequence, then commits the workbook by referencing the
+// resulting uploadSessionId.
+func (api *API) publishWorkbookChunked(ctx context.Context, siteID string, meta Workbook, r io.Reader, size int64, overwrite bool, chunkSize int64, progress ProgressFunc) (retval *Workbook, err error) {
+	uploadSessionID, err := api.initiateFileUpload(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+
+	var sent int64
+	buf := make([]byte, chunkSize)
+	for {
+		n, readErr := io.ReadFull(r, buf)
+		if n > 0 {
+			if appendErr := api.appendToFileUpload(ctx, siteID, uploadSessionID, buf[:n]); appendErr != nil {
+				return nil, appendErr
+			}
+			sent += int64(n)
+			if progress != nil {
+				progress(sent, size)
+			}
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+	}
+
+	return api.commitWorkbookUpload(ctx, siteID, meta, uploadSessionID, overwrite)
+}
+
+// initiateFileUpload starts a new chunked upload session and returns its
+// uploadSessionId.
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Initiate_File_Upload
+func (api *API) initiateFileUpload(ctx context.Context, siteID string) (string, error) {
+	requestURL := fmt.Sprintf("%s/api/%s/sites/%s/fileUploads", api.Server, api.Version, siteID)
+	retval := FileUploadResponse{}
+	err := api.makeStreamingRequest(ctx, requestURL, POST, nil, nil, &retval)
+	return retval.FileUpload.UploadSessionID, err
+}
+
+// appendToFileUpload appends one chunk to an in-progress upload session.
+// http://onlinehelp.tableau.com/current/api/rest_api/en-us/help.htm#REST/rest_api_ref.htm#Append_to_File_Upload
+func (api *API) appendToFileUpload(ctx context.Context, siteID, uploadSessionID string, chunk []byte) error {
+	requestURL := fmt.Sprintf("%s/api/%s/sites/%s/fileUploads/%s", api.Server, api.Version, siteID, uploadSessionID)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	if err := mw.SetBoundary(api.Boundary); err != nil {
+		return fmt.Errorf("invalid multipart boundary %q: %w", api.Boundary, err)
+	}
+	requestPayloadWriter, err := mw.CreatePart(partHeader("request_payload", "text/xml"))
+	if err != nil {
+		return err
+	}
+	if _, err = requestPayloadWriter.Write([]byte{}); err != nil {
+		return err
+	}
+	chunkWriter, err := mw.CreatePart(fileDispositionHeader("tableau_file", "file"))
+	if err != nil {
+		return err
+	}
+	if _, err = chunkWriter.Write(chunk); err != nil {
+		return err
+	}
+	if err = mw.Close(); err != nil {
+		return err
+	}
+
+	headers := map[string]string{content_type_header: fmt.Sprintf("multipart/mixed; boundary=%s", api.Boundary)}
+	return api.makeStreamingRequest(ctx, requestURL, PUT, &body, headers, &FileUploadResponse{})
+}
+
+// commitWorkbookUpload finalizes a chunked upload, publishing the workbook
+// from the data accumulated in uploadSessionID.
+func (api *API) commitWorkbookUpload(ctx context.Context, siteID string, meta Workbook, uploadSessionID string, overwrite bool) (retval *Workbook, err error) {
+	requestURL := fmt.Sprintf("%s/api/%s/sites/%s/workbooks?uploadSessionId=%s&workbookType=twbx&overwrite=%v", api.Server, api.Version, siteID, uploadSessionID, overwrite)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	if err := mw.SetBoundary(api.Boundary); err != nil {
+		return nil, fmt.Errorf("invalid multipart boundary %q: %w", api.Boundary, err)
+	}
+	requestPayloadWriter, err := mw.CreatePart(partHeader("request_payload", "text/xml"))
+	if err != nil {
+		return nil, err
+	}
+	workbookRequest := WorkbookCreateRequest{Request: meta}
+	xmlRepresentation, err := workbookRequest.XML()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = requestPayloadWriter.Write(xmlRepresentation); err != nil {
+		return nil, err
+	}
+	if err = mw.Close(); err != nil {
+		return nil, err
+	}
+
+	headers := map[string]string{content_type_header: fmt.Sprintf("multipart/mixed; boundary=%s", api.Boundary)}
+	retval = &Workbook{}
+	err = api.makeStreamingRequest(ctx, requestURL, POST, &body, headers, retval)
+	return retval, err
+}
+
+// partHeader builds the MIME header for a non-file multipart part.
+func partHeader(name, contentType string) map[string][]string {
+	h := make(map[string][]string)
+	h["Content-Disposition"] = []string{fmt.Sprintf("name=%q", name)}
+	h[content_type_header] = []string{contentType}
+	return h
+}
+
+// fileDispositionHeader builds the MIME header for a file multipart part.
+func fileDispositionHeader(name, filename string) map[string][]string {
+	h := make(map[string][]string)
+	h["Content-Disposition"] = []string{fmt.Sprintf("name=%q; filename=%q", name, filename)}
+	h[content_type_header] = []string{"application/octet-stream"}
+	return h
+}
+
+// makeStreamingRequest is the io.Reader-bodied counterpart to makeRequest,
+// used by the streaming publish paths so a large payload is never buffered
+// into a single []byte. It honors ctx for cancellation.
+func (api *API) makeStreamingRequest(ctx context.Context, requestURL string, method string, body io.Reader, headers map[string]string, result interface{}) error {
+	if Debug {
+		fmt.Printf("%s:%v\n", method, requestURL)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, body)
+	if err != nil {
+		return err
+	}
+	for header, headerValue := range headers {
+		req.Header.Add(header, headerValue)
+	}
+	if len(api.AuthToken) > 0 {
+		req.Header.Add(auth_header, api.AuthToken)
+	}
+
+	client := api.httpClient()
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if Debug {
+		fmt.Printf("t4g Response:%v\n", string(respBody))
+	}
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		tErrorResponse := ErrorResponse{}
+		if err := xml.Unmarshal(respBody, &tErrorResponse); err != nil {
+			return err
+		}
+		return newAPIError(requestURL, resp.StatusCode, tErrorResponse.Error.Code, tErrorResponse.Error.Summary, tErrorResponse.Error.Detail)
+	}
+	if result != nil {
+		if err := xml.Unmarshal(respBody, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2013 Matthew Baird
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tableau4go
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPublishWorkbookSmallPayloadUsesSinglePart(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Write([]byte(`<workbook id="wb1" name="Test"/>`))
+	}))
+	defer server.Close()
+
+	api := &API{Server: server.URL, Version: "3.4", Boundary: "test-boundary"}
+	retval, err := api.PublishWorkbook(context.Background(), "site1", Workbook{Name: "Test"}, strings.NewReader("fake-twbx-bytes"), 15, false, nil)
+	if err != nil {
+		t.Fatalf("PublishWorkbook: %v", err)
+	}
+	if retval.ID != "wb1" {
+		t.Fatalf("retval.ID = %q, want wb1", retval.ID)
+	}
+	if len(requests) != 1 || requests[0] != "POST /api/3.4/sites/site1/workbooks" {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+}
+
+func TestPublishWorkbookLargePayloadUsesChunkedSequence(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/fileUploads"):
+			w.Write([]byte(`<tsResponse><fileUpload uploadSessionId="sess1"/></tsResponse>`))
+		case strings.Contains(r.URL.Path, "/fileUploads/"):
+			w.Write([]byte(`<tsResponse><fileUpload uploadSessionId="sess1"/></tsResponse>`))
+		case strings.HasSuffix(r.URL.Path, "/workbooks"):
+			w.Write([]byte(`<workbook id="wb1" name="Test"/>`))
+		default:
+			t.Errorf("unexpected request path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	api := &API{Server: server.URL, Version: "3.4", Boundary: "test-boundary"}
+	payload := bytes.Repeat([]byte("x"), 25)
+	opts := &UploadOptions{ChunkSize: 10}
+	var progressed []int64
+	opts.Progress = func(sent, total int64) { progressed = append(progressed, sent) }
+
+	retval, err := api.PublishWorkbook(context.Background(), "site1", Workbook{Name: "Test"}, bytes.NewReader(payload), int64(len(payload)), false, opts)
+	if err != nil {
+		t.Fatalf("PublishWorkbook: %v", err)
+	}
+	if retval.ID != "wb1" {
+		t.Fatalf("retval.ID = %q, want wb1", retval.ID)
+	}
+
+	if len(requests) != 5 {
+		t.Fatalf("expected 1 initiate + 3 append + 1 commit = 5 requests, got %d: %v", len(requests), requests)
+	}
+	if requests[0] != fmt.Sprintf("POST /api/3.4/sites/site1/fileUploads") {
+		t.Fatalf("first request = %q, want initiate", requests[0])
+	}
+	for _, req := range requests[1:4] {
+		if !strings.HasPrefix(req, "PUT ") {
+			t.Fatalf("expected append requests to be PUT, got %q", req)
+		}
+	}
+	if !strings.HasSuffix(requests[4], "/workbooks") {
+		t.Fatalf("last request = %q, want commit to /workbooks", requests[4])
+	}
+	if len(progressed) != 3 || progressed[2] != 25 {
+		t.Fatalf("unexpected progress callback sequence: %v", progressed)
+	}
+}